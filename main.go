@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
 	"time"
 
 	"github.com/aeytom/qmc5883l/qmc5883l"
+	"github.com/aeytom/qmc5883l/qmc5883l/sink"
 
 	"github.com/stianeikeland/go-rpio"
 )
@@ -28,19 +30,75 @@ func main() {
 	Verbose = flag.Bool("verbose", false, "provide more debugging output")
 	Testing = flag.Bool("test", false, "do not write influxdb")
 
+	influxURL := getEnvArg("INFLUX_URL", "influx-url", "", "InfluxDB base URL, e.g. http://localhost:8086")
+	influxBucket := getEnvArg("INFLUX_BUCKET", "influx-bucket", "qmc5883l", "InfluxDB v2 bucket (or v1 database)")
+	influxOrg := getEnvArg("INFLUX_ORG", "influx-org", "", "InfluxDB v2 organization")
+	influxToken := getEnvArg("INFLUX_TOKEN", "influx-token", "", "InfluxDB v2 API token; presence selects the v2 write API")
+	promListen := getEnvArg("PROM_LISTEN", "prom-listen", "", "listen address for the Prometheus /metrics endpoint, e.g. :9110")
+
 	flag.Parse()
 
-	sensor := qmc5883l.New(qmc5883l.DfltBus, qmc5883l.DfltAddress)
-	sensor.SetMode(qmc5883l.ModeCONT, qmc5883l.Odr200HZ, qmc5883l.Rng8G, qmc5883l.Osr512)
+	sensor, err := qmc5883l.New(qmc5883l.DfltBus, qmc5883l.DfltAddress)
+	if err != nil {
+		log.Fatal(err)
+	}
+	magRange := byte(qmc5883l.Rng8G)
+	sensor.SetMode(qmc5883l.ModeCONT, qmc5883l.Odr200HZ, magRange, qmc5883l.Osr512)
+
+	sinks := buildSinks(*influxURL, *influxBucket, *influxOrg, *influxToken, *promListen, magRange)
+	defer func() {
+		for _, s := range sinks {
+			s.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	samples, err := sensor.Start(ctx, time.Millisecond*100)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for s := range samples {
+		log.Printf("x=%v y=%v z=%v overflow=%v", s.X, s.Y, s.Z, s.Overflow)
+		for _, sk := range sinks {
+			if err := sk.Write(s); err != nil {
+				log.Printf("sink write failed: %v", err)
+			}
+		}
+	}
+}
+
+// buildSinks assembles the configured metric sinks from the resolved env
+// vars. The InfluxDB sink is skipped in -test mode. magRange must match the
+// sensor's configured output range, so the Prometheus sink's Gauss scaling
+// can't drift out of sync with it.
+func buildSinks(influxURL, influxBucket, influxOrg, influxToken, promListen string, magRange byte) []sink.Sink {
+	var sinks []sink.Sink
 
-	for {
-		x, y, z, err := sensor.GetMagnetRaw()
-		log.Printf("x=%v y=%v z=%v err=%v", x, y, z, err)
-		time.Sleep(time.Millisecond * 100)
+	sinks = append(sinks, sink.NewStdout(os.Stdout))
+
+	if influxURL != "" && !*Testing {
+		if influxToken != "" {
+			sinks = append(sinks, sink.NewInfluxV2(influxURL, influxBucket, influxOrg, influxToken))
+		} else {
+			sinks = append(sinks, sink.NewInfluxV1(influxURL, influxBucket))
+		}
+	}
+
+	if promListen != "" {
+		prom, err := sink.NewPrometheus(promListen, magRange)
+		if err != nil {
+			log.Printf("prometheus sink disabled: %v", err)
+		} else {
+			sinks = append(sinks, prom)
+		}
 	}
+
+	return sinks
 }
 
-//
 func getEnvArg(env string, arg string, dflt string, usage string) *string {
 	ev, avail := os.LookupEnv(env)
 	if avail {
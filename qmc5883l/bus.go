@@ -0,0 +1,9 @@
+package qmc5883l
+
+// Bus abstracts the register-level I²C access used by the driver.
+type Bus interface {
+	ReadRegU8(reg byte) (byte, error)
+	WriteRegU8(reg byte, val byte) error
+	ReadRegU16LE(reg byte) (uint16, error)
+	Close() error
+}
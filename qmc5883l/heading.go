@@ -0,0 +1,104 @@
+package qmc5883l
+
+import (
+	"math"
+	"time"
+)
+
+// Calibration holds the per-axis hard-iron envelope and local declination.
+type Calibration struct {
+	MinX, MaxX  int16
+	MinY, MaxY  int16
+	Declination float64 // radians, added to the computed heading
+}
+
+// SetCalibration installs a previously captured Calibration.
+func (q *QMC5883L) SetCalibration(c Calibration) {
+	q.calibration = c
+	q.envelopeSeeded = true
+}
+
+// GetCalibration returns the current Calibration.
+func (q *QMC5883L) GetCalibration() Calibration {
+	return q.calibration
+}
+
+// Calibrate samples the sensor for d, widening the hard-iron envelope.
+func (q *QMC5883L) Calibrate(d time.Duration) error {
+	deadline := time.Now().Add(d)
+	for time.Now().Before(deadline) {
+		x, y, _, err := q.GetMagnetRaw()
+		if err != nil {
+			return err
+		}
+		q.trackEnvelope(x, y)
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// trackEnvelope widens the calibration min/max envelope with a raw sample.
+func (q *QMC5883L) trackEnvelope(x, y int16) {
+	c := &q.calibration
+	if !q.envelopeSeeded {
+		c.MinX, c.MaxX = x, x
+		c.MinY, c.MaxY = y, y
+		q.envelopeSeeded = true
+		return
+	}
+	if x < c.MinX {
+		c.MinX = x
+	}
+	if x > c.MaxX {
+		c.MaxX = x
+	}
+	if y < c.MinY {
+		c.MinY = y
+	}
+	if y > c.MaxY {
+		c.MaxY = y
+	}
+}
+
+// GetHeading returns the compass heading in degrees, normalized to [0, 360).
+func (q *QMC5883L) GetHeading() (float64, error) {
+	x, y, _, err := q.GetMagnetRaw()
+	if err != nil {
+		return 0, err
+	}
+	q.trackEnvelope(x, y)
+	return q.heading(x, y), nil
+}
+
+// heading turns raw x/y counts into a calibrated heading in degrees.
+func (q *QMC5883L) heading(x, y int16) float64 {
+	c := q.calibration
+	offX := (float64(c.MinX) + float64(c.MaxX)) / 2
+	offY := (float64(c.MinY) + float64(c.MaxY)) / 2
+
+	rad := math.Atan2(float64(y)-offY, float64(x)-offX) + c.Declination
+	if rad < 0 {
+		rad += 2 * math.Pi
+	} else if rad >= 2*math.Pi {
+		rad -= 2 * math.Pi
+	}
+	return rad * 180 / math.Pi
+}
+
+// Headings delivers a heading on the returned channel every interval, until
+// the sensor reports a read error.
+func (q *QMC5883L) Headings(interval time.Duration) <-chan float64 {
+	out := make(chan float64)
+	go func() {
+		defer close(out)
+		for {
+			h, err := q.GetHeading()
+			if err != nil {
+				return
+			}
+			out <- h
+			time.Sleep(interval)
+		}
+	}()
+	return out
+}
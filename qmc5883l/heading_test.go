@@ -0,0 +1,18 @@
+package qmc5883l
+
+import "testing"
+
+func TestTrackEnvelopeSeedsFromFirstSample(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+
+	q.trackEnvelope(500, -500)
+
+	c := q.GetCalibration()
+	if c.MinX != 500 || c.MaxX != 500 || c.MinY != -500 || c.MaxY != -500 {
+		t.Errorf("GetCalibration() = %+v, want envelope seeded to (500, -500)", c)
+	}
+}
@@ -3,8 +3,8 @@ package qmc5883l
 // qmc58883l implements http://wiki.sunfounder.cc/images/7/72/QMC5883L-Datasheet-1.0.pdf
 
 import (
+	"context"
 	"errors"
-	"log"
 
 	"github.com/d2r2/go-i2c"
 	"github.com/d2r2/go-logger"
@@ -69,16 +69,23 @@ const (
 
 // QMC5883L chip handle
 type QMC5883L struct {
-	i2cBus           int
-	address          byte
-	outputDataRate   byte
-	outputRange      byte
-	oversamplingRate byte
-	bus              *i2c.I2C
+	i2cBus               int
+	address              byte
+	outputDataRate       byte
+	outputRange          byte
+	oversamplingRate     byte
+	bus                  Bus
+	calibration          Calibration
+	envelopeSeeded       bool
+	temperatureReference float64
+	stop                 context.CancelFunc
+	dropped              uint64
 }
 
-// New initilize structure
-func New(i2cBus int, address uint8) *QMC5883L {
+// New initializes the structure using the default d2r2/go-i2c transport.
+// Callers on platforms without a Linux i2c-dev bus (or who want to unit
+// test against a fake) should use NewWithBus instead.
+func New(i2cBus int, address uint8) (*QMC5883L, error) {
 	if i2cBus == 0 {
 		i2cBus = DfltBus
 	}
@@ -90,30 +97,42 @@ func New(i2cBus int, address uint8) *QMC5883L {
 
 	bus, err := i2c.NewI2C(address, i2cBus)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
+	q, err := NewWithBus(bus)
+	if err != nil {
+		return nil, err
+	}
+	q.i2cBus = i2cBus
+	q.address = address
+	return q, nil
+}
+
+// NewWithBus initializes the structure using a caller-supplied Bus, e.g. a
+// periph.io or tinygo transport, or a fake bus in tests.
+func NewWithBus(bus Bus) (*QMC5883L, error) {
 	q := QMC5883L{
-		i2cBus:           i2cBus,
-		address:          address,
 		outputDataRate:   Odr10HZ,
 		outputRange:      Rng2G,
 		oversamplingRate: Osr512,
 		bus:              bus,
 	}
 
-	err = bus.WriteRegU8(RegRstPeriod, 0x01)
+	err := bus.WriteRegU8(RegRstPeriod, 0x01)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	err = bus.WriteRegU8(RegControl2, SoftRst)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	q.SetMode(ModeCONT, q.outputDataRate, q.outputRange, q.oversamplingRate)
-	return &q
+	if err := q.SetMode(ModeCONT, q.outputDataRate, q.outputRange, q.oversamplingRate); err != nil {
+		return nil, err
+	}
+	return &q, nil
 }
 
 // Close the I²C handle
@@ -180,6 +199,37 @@ func (q *QMC5883L) GetMagnetRaw() (int16, int16, int16, error) {
 	return x, y, z, err
 }
 
+// GetStatus reads and decodes RegStatus1, so callers can poll data-ready
+// without going through GetMagnetRaw.
+func (q *QMC5883L) GetStatus() (drdy bool, ovl bool, dor bool, err error) {
+	status, err := q.ReadRegistry(RegStatus1)
+	if err != nil {
+		return false, false, false, err
+	}
+	return status&StatDRDY == StatDRDY, status&StatOVL == StatOVL, status&StatDOR == StatDOR, nil
+}
+
+// GetMagnetGauss reads the 3 axis values and scales them to Gauss using the
+// currently configured output range.
+func (q *QMC5883L) GetMagnetGauss() (x float64, y float64, z float64, err error) {
+	rx, ry, rz, err := q.GetMagnetRaw()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	lsbPerGauss := LSBPerGauss(q.outputRange)
+	return float64(rx) / lsbPerGauss, float64(ry) / lsbPerGauss, float64(rz) / lsbPerGauss, nil
+}
+
+// LSBPerGauss returns the sensor's output scale for rng (Rng2G or Rng8G), in
+// LSB/Gauss, so callers converting raw counts outside the driver (e.g. a
+// metric sink) can't drift out of sync with GetMagnetGauss.
+func LSBPerGauss(rng byte) float64 {
+	if rng == Rng8G {
+		return 3000.0
+	}
+	return 12000.0
+}
+
 // Complement2 Calculate the 2's complement of a two bytes value.
 func complement2(val uint16) int16 {
 	if val >= 0x8000 {
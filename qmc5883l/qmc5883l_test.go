@@ -0,0 +1,218 @@
+package qmc5883l
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeBus is an in-memory Bus for testing, with optional error injection
+// per register. Safe for concurrent use so it can back the background
+// sampler's goroutine.
+type fakeBus struct {
+	mu      sync.Mutex
+	regs    map[byte]byte
+	words   map[byte]uint16
+	errRegs map[byte]error
+	closed  bool
+}
+
+func newFakeBus() *fakeBus {
+	return &fakeBus{
+		regs:    map[byte]byte{},
+		words:   map[byte]uint16{},
+		errRegs: map[byte]error{},
+	}
+}
+
+func (f *fakeBus) ReadRegU8(reg byte) (byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errRegs[reg]; err != nil {
+		return 0, err
+	}
+	return f.regs[reg], nil
+}
+
+func (f *fakeBus) WriteRegU8(reg byte, val byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errRegs[reg]; err != nil {
+		return err
+	}
+	f.regs[reg] = val
+	return nil
+}
+
+func (f *fakeBus) ReadRegU16LE(reg byte) (uint16, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.errRegs[reg]; err != nil {
+		return 0, err
+	}
+	return f.words[reg], nil
+}
+
+func (f *fakeBus) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *fakeBus) setErr(reg byte, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errRegs[reg] = err
+}
+
+func TestNewWithBus(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	if bus.regs[RegControl1] != ModeCONT|Odr10HZ|Rng2G|Osr512 {
+		t.Errorf("control register not set to default mode, got 0x%02x", bus.regs[RegControl1])
+	}
+	if bus.regs[RegControl2] != SoftRst {
+		t.Errorf("soft reset not issued, got 0x%02x", bus.regs[RegControl2])
+	}
+	if q.bus != bus {
+		t.Errorf("q.bus = %v, want %v", q.bus, bus)
+	}
+}
+
+func TestNewWithBusResetError(t *testing.T) {
+	bus := newFakeBus()
+	bus.errRegs[RegRstPeriod] = errors.New("i2c timeout")
+
+	if _, err := NewWithBus(bus); err == nil {
+		t.Fatal("expected error when RegRstPeriod write fails, got nil")
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+
+	if err := q.SetMode(ModeCONT, Odr200HZ, Rng8G, Osr64); err != nil {
+		t.Fatalf("SetMode returned error: %v", err)
+	}
+	if want := byte(ModeCONT | Odr200HZ | Rng8G | Osr64); bus.regs[RegControl1] != want {
+		t.Errorf("control register = 0x%02x, want 0x%02x", bus.regs[RegControl1], want)
+	}
+}
+
+func TestGetMagnetRawOverflow(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.regs[RegStatus1] = StatOVL
+
+	if _, _, _, err := q.GetMagnetRaw(); err == nil {
+		t.Fatal("expected overflow error, got nil")
+	}
+}
+
+func TestGetMagnetRawDataReady(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.regs[RegStatus1] = StatDRDY
+	bus.words[RegXoutLSB] = 100
+	bus.words[RegYoutLSB] = 200
+	bus.words[RegZoutLSB] = 300
+
+	x, y, z, err := q.GetMagnetRaw()
+	if err != nil {
+		t.Fatalf("GetMagnetRaw returned error: %v", err)
+	}
+	if x != 100 || y != 200 || z != 300 {
+		t.Errorf("GetMagnetRaw() = %d, %d, %d, want 100, 200, 300", x, y, z)
+	}
+}
+
+func TestGetMagnetRawStatusError(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.errRegs[RegStatus1] = errors.New("i2c timeout")
+
+	if _, _, _, err := q.GetMagnetRaw(); err == nil {
+		t.Fatal("expected status read error, got nil")
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.regs[RegStatus1] = StatDRDY | StatDOR
+
+	drdy, ovl, dor, err := q.GetStatus()
+	if err != nil {
+		t.Fatalf("GetStatus returned error: %v", err)
+	}
+	if !drdy || ovl || !dor {
+		t.Errorf("GetStatus() = drdy=%v ovl=%v dor=%v, want drdy=true ovl=false dor=true", drdy, ovl, dor)
+	}
+}
+
+func TestGetStatusError(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.errRegs[RegStatus1] = errors.New("i2c timeout")
+
+	if _, _, _, err := q.GetStatus(); err == nil {
+		t.Fatal("expected status read error, got nil")
+	}
+}
+
+func TestGetMagnetGauss(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.regs[RegStatus1] = StatDRDY
+	bus.words[RegXoutLSB] = 6000
+	bus.words[RegYoutLSB] = 12000
+	bus.words[RegZoutLSB] = 24000
+
+	if err := q.SetMode(ModeCONT, Odr200HZ, Rng2G, Osr512); err != nil {
+		t.Fatalf("SetMode returned error: %v", err)
+	}
+	x, y, z, err := q.GetMagnetGauss()
+	if err != nil {
+		t.Fatalf("GetMagnetGauss returned error: %v", err)
+	}
+	if x != 0.5 || y != 1 || z != 2 {
+		t.Errorf("GetMagnetGauss() = %v, %v, %v, want 0.5, 1, 2 (Rng2G scaling)", x, y, z)
+	}
+
+	if err := q.SetMode(ModeCONT, Odr200HZ, Rng8G, Osr512); err != nil {
+		t.Fatalf("SetMode returned error: %v", err)
+	}
+	x, y, z, err = q.GetMagnetGauss()
+	if err != nil {
+		t.Fatalf("GetMagnetGauss returned error: %v", err)
+	}
+	if x != 2 || y != 4 || z != 8 {
+		t.Errorf("GetMagnetGauss() = %v, %v, %v, want 2, 4, 8 (Rng8G scaling)", x, y, z)
+	}
+}
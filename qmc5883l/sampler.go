@@ -0,0 +1,128 @@
+package qmc5883l
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// maxConsecutiveErrors triggers a SoftRst and mode reapply.
+const maxConsecutiveErrors = 5
+
+// sampleBufferSize is the capacity of the channel returned by Start.
+const sampleBufferSize = 16
+
+// Sample is a single timestamped magnetometer reading.
+type Sample struct {
+	X, Y, Z  int16
+	T        time.Time
+	Overflow bool
+}
+
+// Start polls the sensor at interval and delivers Samples on the returned
+// channel until Stop is called or ctx is cancelled.
+func (q *QMC5883L) Start(ctx context.Context, interval time.Duration) (<-chan Sample, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	q.stop = cancel
+
+	out := make(chan Sample, sampleBufferSize)
+	go q.sample(ctx, out, interval)
+	return out, nil
+}
+
+// Stop ends the background sampler started with Start.
+func (q *QMC5883L) Stop() {
+	if q.stop != nil {
+		q.stop()
+	}
+}
+
+// Dropped returns the number of Samples discarded for a full channel.
+func (q *QMC5883L) Dropped() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
+
+func (q *QMC5883L) sample(ctx context.Context, out chan Sample, interval time.Duration) {
+	defer close(out)
+
+	consecutiveErrors := 0
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		drdy, ovl, dor, statusErr := q.GetStatus()
+		if statusErr != nil || dor {
+			consecutiveErrors++
+			if consecutiveErrors >= maxConsecutiveErrors {
+				q.recover()
+				consecutiveErrors = 0
+			}
+			continue
+		}
+		if !drdy {
+			continue
+		}
+		if ovl {
+			consecutiveErrors = 0
+			q.deliver(out, Sample{T: time.Now(), Overflow: true})
+			continue
+		}
+
+		x, y, z, err := q.readXYZ()
+		if err != nil {
+			consecutiveErrors++
+			if consecutiveErrors >= maxConsecutiveErrors {
+				q.recover()
+				consecutiveErrors = 0
+			}
+			continue
+		}
+		consecutiveErrors = 0
+		q.deliver(out, Sample{X: x, Y: y, Z: z, T: time.Now()})
+	}
+}
+
+// readXYZ reads the 3 axis words directly, skipping GetMagnetRaw's own
+// status re-read.
+func (q *QMC5883L) readXYZ() (x, y, z int16, err error) {
+	x, err = q.ReadWord(RegXoutLSB)
+	if err != nil {
+		return
+	}
+	y, err = q.ReadWord(RegYoutLSB)
+	if err != nil {
+		return
+	}
+	z, err = q.ReadWord(RegZoutLSB)
+	return
+}
+
+// deliver pushes s onto out, dropping the oldest queued Sample if full.
+func (q *QMC5883L) deliver(out chan Sample, s Sample) {
+	select {
+	case out <- s:
+		return
+	default:
+	}
+	select {
+	case <-out:
+		atomic.AddUint64(&q.dropped, 1)
+	default:
+	}
+	select {
+	case out <- s:
+	default:
+	}
+}
+
+// recover issues a soft reset and re-applies the last SetMode configuration.
+func (q *QMC5883L) recover() {
+	_ = q.bus.WriteRegU8(RegControl2, SoftRst)
+	_ = q.SetMode(ModeCONT, q.outputDataRate, q.outputRange, q.oversamplingRate)
+}
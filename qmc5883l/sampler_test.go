@@ -0,0 +1,77 @@
+package qmc5883l
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeliverDropsOldestWhenFull(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+
+	out := make(chan Sample, 1)
+	q.deliver(out, Sample{X: 1})
+	q.deliver(out, Sample{X: 2})
+
+	if got := q.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+	if got := <-out; got.X != 2 {
+		t.Errorf("queued sample X = %d, want 2 (oldest should have been dropped)", got.X)
+	}
+}
+
+func TestRecoverReappliesMode(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	if err := q.SetMode(ModeCONT, Odr200HZ, Rng8G, Osr64); err != nil {
+		t.Fatalf("SetMode returned error: %v", err)
+	}
+	bus.regs[RegControl2] = 0 // clear the SoftRst written during construction
+
+	q.recover()
+
+	if bus.regs[RegControl2] != SoftRst {
+		t.Errorf("RegControl2 = 0x%02x after recover, want SoftRst", bus.regs[RegControl2])
+	}
+	if want := byte(ModeCONT | Odr200HZ | Rng8G | Osr64); bus.regs[RegControl1] != want {
+		t.Errorf("RegControl1 = 0x%02x after recover, want 0x%02x (mode re-applied)", bus.regs[RegControl1], want)
+	}
+}
+
+func TestSampleRecoversAfterConsecutiveStatusErrors(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.regs[RegControl2] = 0 // clear the SoftRst written during construction
+	bus.setErr(RegStatus1, errors.New("i2c timeout"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := q.Start(ctx, time.Millisecond); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	deadline := time.After(time.Second)
+	for {
+		if reg, _ := bus.ReadRegU8(RegControl2); reg == SoftRst {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("recover() did not fire within deadline")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aeytom/qmc5883l/qmc5883l"
+)
+
+// influxBatchSize is how many samples are buffered before a write is flushed.
+const influxBatchSize = 20
+
+// influxFlushInterval bounds how long a partial batch waits before it is
+// flushed anyway, so samples aren't held back indefinitely on a slow sensor.
+const influxFlushInterval = 5 * time.Second
+
+// influxMaxRetries is how many times in total a batch's HTTP write is
+// attempted before the batch is dropped.
+const influxMaxRetries = 3
+
+// Influx writes Samples as batched, retried InfluxDB line protocol.
+type Influx struct {
+	client   *http.Client
+	writeURL string
+	token    string
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	count   int
+	flushed time.Time
+}
+
+// NewInfluxV1 targets a v1 `/write?db=<database>` endpoint.
+func NewInfluxV1(baseURL, database string) *Influx {
+	return &Influx{
+		client:   &http.Client{Timeout: 5 * time.Second},
+		writeURL: fmt.Sprintf("%s/write?db=%s", baseURL, database),
+		flushed:  time.Now(),
+	}
+}
+
+// NewInfluxV2 targets a v2 `/api/v2/write?bucket=<bucket>` endpoint.
+func NewInfluxV2(baseURL, bucket, org, token string) *Influx {
+	return &Influx{
+		client:   &http.Client{Timeout: 5 * time.Second},
+		writeURL: fmt.Sprintf("%s/api/v2/write?bucket=%s&org=%s&precision=ns", baseURL, bucket, org),
+		token:    token,
+		flushed:  time.Now(),
+	}
+}
+
+// Write appends s to the batch, flushing once it's full or stale.
+func (i *Influx) Write(s qmc5883l.Sample) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	fmt.Fprintf(&i.buf, "qmc5883l x=%di,y=%di,z=%di,overflow=%t %d\n",
+		s.X, s.Y, s.Z, s.Overflow, s.T.UnixNano())
+	i.count++
+
+	if i.count < influxBatchSize && time.Since(i.flushed) < influxFlushInterval {
+		return nil
+	}
+	return i.flushLocked()
+}
+
+// flushLocked sends the buffered batch and resets it. Callers must hold mu.
+func (i *Influx) flushLocked() error {
+	if i.count == 0 {
+		return nil
+	}
+	body := i.buf.Bytes()
+
+	var err error
+	for attempt := 0; attempt < influxMaxRetries; attempt++ {
+		err = i.post(body)
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
+	}
+
+	i.buf.Reset()
+	i.count = 0
+	i.flushed = time.Now()
+	return err
+}
+
+func (i *Influx) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, i.writeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if i.token != "" {
+		req.Header.Set("Authorization", "Token "+i.token)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("influx write failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// Close flushes any buffered samples.
+func (i *Influx) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.flushLocked()
+}
@@ -0,0 +1,92 @@
+package sink
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aeytom/qmc5883l/qmc5883l"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestInfluxWriteFlushesAtBatchSize(t *testing.T) {
+	var posts int32
+	i := &Influx{
+		client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&posts, 1)
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+		})},
+		writeURL: "http://influx.example/write?db=qmc5883l",
+		flushed:  time.Now(),
+	}
+
+	for n := 0; n < influxBatchSize-1; n++ {
+		if err := i.Write(qmc5883l.Sample{X: int16(n)}); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&posts); got != 0 {
+		t.Fatalf("posts = %d before batch is full, want 0", got)
+	}
+
+	if err := i.Write(qmc5883l.Sample{X: influxBatchSize}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&posts); got != 1 {
+		t.Errorf("posts = %d after batch fills, want 1", got)
+	}
+	if i.count != 0 {
+		t.Errorf("count = %d after flush, want 0", i.count)
+	}
+}
+
+func TestInfluxWriteRetriesOnFailure(t *testing.T) {
+	var posts int32
+	i := &Influx{
+		client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&posts, 1)
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: http.NoBody}, nil
+		})},
+		writeURL: "http://influx.example/write?db=qmc5883l",
+		flushed:  time.Now(),
+	}
+
+	for n := 0; n < influxBatchSize; n++ {
+		if err := i.Write(qmc5883l.Sample{X: int16(n)}); n < influxBatchSize-1 && err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&posts); got != influxMaxRetries {
+		t.Errorf("posts = %d, want %d attempts", got, influxMaxRetries)
+	}
+}
+
+func TestInfluxClose(t *testing.T) {
+	var posts int32
+	i := &Influx{
+		client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&posts, 1)
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+		})},
+		writeURL: "http://influx.example/write?db=qmc5883l",
+		flushed:  time.Now(),
+	}
+
+	if err := i.Write(qmc5883l.Sample{X: 1}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := i.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&posts); got != 1 {
+		t.Errorf("posts = %d after Close, want 1 (pending batch flushed)", got)
+	}
+}
@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"math"
+	"net"
+	"net/http"
+
+	"github.com/aeytom/qmc5883l/qmc5883l"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus exposes the most recent Sample as gauges on a `/metrics`
+// endpoint. Heading is uncalibrated; use QMC5883L.GetHeading for that.
+type Prometheus struct {
+	lsbPerGauss float64
+	fieldGauss  *prometheus.GaugeVec
+	heading     prometheus.Gauge
+	server      *http.Server
+}
+
+// NewPrometheus starts an HTTP server on listenAddr serving `/metrics`. rng
+// must match the sensor's configured output range (qmc5883l.Rng2G or
+// qmc5883l.Rng8G), so the Gauss scaling can't drift out of sync with it.
+func NewPrometheus(listenAddr string, rng byte) (*Prometheus, error) {
+	ln, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Prometheus{
+		lsbPerGauss: qmc5883l.LSBPerGauss(rng),
+		fieldGauss: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "qmc5883l_field_gauss",
+			Help: "Magnetic field strength in Gauss, per axis.",
+		}, []string{"axis"}),
+		heading: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "qmc5883l_heading_degrees",
+			Help: "Compass heading in degrees, normalized to [0, 360).",
+		}),
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(p.fieldGauss, p.heading)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	p.server = &http.Server{Addr: ln.Addr().String(), Handler: mux}
+	go p.server.Serve(ln)
+
+	return p, nil
+}
+
+// Write updates the gauges from s.
+func (p *Prometheus) Write(s qmc5883l.Sample) error {
+	if s.Overflow {
+		return nil
+	}
+	x := float64(s.X) / p.lsbPerGauss
+	y := float64(s.Y) / p.lsbPerGauss
+	z := float64(s.Z) / p.lsbPerGauss
+
+	p.fieldGauss.WithLabelValues("x").Set(x)
+	p.fieldGauss.WithLabelValues("y").Set(y)
+	p.fieldGauss.WithLabelValues("z").Set(z)
+
+	heading := math.Atan2(y, x)
+	if heading < 0 {
+		heading += 2 * math.Pi
+	}
+	p.heading.Set(heading * 180 / math.Pi)
+	return nil
+}
+
+// Close shuts down the `/metrics` HTTP server.
+func (p *Prometheus) Close() error {
+	return p.server.Close()
+}
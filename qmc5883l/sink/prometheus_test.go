@@ -0,0 +1,57 @@
+package sink
+
+import (
+	"testing"
+
+	"github.com/aeytom/qmc5883l/qmc5883l"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusWrite(t *testing.T) {
+	p, err := NewPrometheus(":0", qmc5883l.Rng8G)
+	if err != nil {
+		t.Fatalf("NewPrometheus returned error: %v", err)
+	}
+	defer p.Close()
+
+	if err := p.Write(qmc5883l.Sample{X: 3000, Y: 0, Z: 1500}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(p.fieldGauss.WithLabelValues("x")); got != 1 {
+		t.Errorf("x gauge = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(p.fieldGauss.WithLabelValues("z")); got != 0.5 {
+		t.Errorf("z gauge = %v, want 0.5", got)
+	}
+	if got := testutil.ToFloat64(p.heading); got != 0 {
+		t.Errorf("heading gauge = %v, want 0", got)
+	}
+}
+
+func TestPrometheusWriteOverflowSkipped(t *testing.T) {
+	p, err := NewPrometheus(":0", qmc5883l.Rng8G)
+	if err != nil {
+		t.Fatalf("NewPrometheus returned error: %v", err)
+	}
+	defer p.Close()
+
+	if err := p.Write(qmc5883l.Sample{X: 3000, Overflow: true}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if got := testutil.ToFloat64(p.fieldGauss.WithLabelValues("x")); got != 0 {
+		t.Errorf("x gauge = %v, want 0 (overflow sample should be skipped)", got)
+	}
+}
+
+func TestNewPrometheusListenError(t *testing.T) {
+	first, err := NewPrometheus(":0", qmc5883l.Rng8G)
+	if err != nil {
+		t.Fatalf("NewPrometheus returned error: %v", err)
+	}
+	defer first.Close()
+
+	if _, err := NewPrometheus(first.server.Addr, qmc5883l.Rng8G); err == nil {
+		t.Fatal("expected error binding an already-listening address, got nil")
+	}
+}
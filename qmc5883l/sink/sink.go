@@ -0,0 +1,10 @@
+// Package sink delivers qmc5883l.Sample readings to external metric systems.
+package sink
+
+import "github.com/aeytom/qmc5883l/qmc5883l"
+
+// Sink forwards Samples to an external system.
+type Sink interface {
+	Write(s qmc5883l.Sample) error
+	Close() error
+}
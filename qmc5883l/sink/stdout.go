@@ -0,0 +1,29 @@
+package sink
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aeytom/qmc5883l/qmc5883l"
+)
+
+// Stdout writes one JSON object per Sample to the wrapped writer.
+type Stdout struct {
+	w io.Writer
+}
+
+// NewStdout returns a Sink that writes line-oriented JSON (JSONL) to w.
+func NewStdout(w io.Writer) *Stdout {
+	return &Stdout{w: w}
+}
+
+// Write encodes s as a single JSON line.
+func (s *Stdout) Write(sample qmc5883l.Sample) error {
+	enc := json.NewEncoder(s.w)
+	return enc.Encode(sample)
+}
+
+// Close is a no-op; Stdout does not own the underlying writer.
+func (s *Stdout) Close() error {
+	return nil
+}
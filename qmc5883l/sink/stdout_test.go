@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aeytom/qmc5883l/qmc5883l"
+)
+
+func TestStdoutWrite(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewStdout(&buf)
+
+	sample := qmc5883l.Sample{X: 1, Y: 2, Z: 3}
+	if err := s.Write(sample); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	var got qmc5883l.Sample
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if got != sample {
+		t.Errorf("decoded sample = %+v, want %+v", got, sample)
+	}
+}
+
+func TestStdoutClose(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewStdout(&buf)
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package qmc5883l
+
+// tempLSBPerDegree is the datasheet's temperature sensitivity.
+const tempLSBPerDegree = 100.0
+
+// SetTemperatureReference calibrates GetTemperature against a known ambient
+// temperature in °C.
+func (q *QMC5883L) SetTemperatureReference(celsius float64) error {
+	raw, err := q.ReadWord(RegToutLSB)
+	if err != nil {
+		return err
+	}
+	q.temperatureReference = celsius - float64(raw)/tempLSBPerDegree
+	return nil
+}
+
+// GetTemperature reads the on-die temperature in °C.
+func (q *QMC5883L) GetTemperature() (float64, error) {
+	raw, err := q.ReadWord(RegToutLSB)
+	if err != nil {
+		return 0, err
+	}
+	return float64(raw)/tempLSBPerDegree + q.temperatureReference, nil
+}
@@ -0,0 +1,57 @@
+package qmc5883l
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetTemperature(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.words[RegToutLSB] = 100
+
+	got, err := q.GetTemperature()
+	if err != nil {
+		t.Fatalf("GetTemperature returned error: %v", err)
+	}
+	if got != 1 {
+		t.Errorf("GetTemperature() = %v, want 1", got)
+	}
+}
+
+func TestSetTemperatureReference(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.words[RegToutLSB] = 100
+
+	if err := q.SetTemperatureReference(25); err != nil {
+		t.Fatalf("SetTemperatureReference returned error: %v", err)
+	}
+
+	got, err := q.GetTemperature()
+	if err != nil {
+		t.Fatalf("GetTemperature returned error: %v", err)
+	}
+	if got != 25 {
+		t.Errorf("GetTemperature() = %v, want 25 after reference calibration", got)
+	}
+}
+
+func TestSetTemperatureReferenceError(t *testing.T) {
+	bus := newFakeBus()
+	q, err := NewWithBus(bus)
+	if err != nil {
+		t.Fatalf("NewWithBus returned error: %v", err)
+	}
+	bus.errRegs[RegToutLSB] = errors.New("i2c timeout")
+
+	if err := q.SetTemperatureReference(25); err == nil {
+		t.Fatal("expected error when RegToutLSB read fails, got nil")
+	}
+}